@@ -0,0 +1,303 @@
+package tesla
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"golang.org/x/oauth2"
+)
+
+// DefaultBaseURL is a base URL for the Tesla API that works in North
+// America and Asia-Pacific.
+//
+// Most people should use their own deployment of Tesla's
+// [vehicle-command].
+//
+// [vehicle-command]: https://github.com/teslamotors/vehicle-command
+var DefaultBaseURL, _ = url.Parse("https://fleet-api.prd.na.vn.cloud.tesla.com")
+
+type Client struct {
+	hc           *http.Client
+	baseURL      *url.URL
+	tokenSource  oauth2.TokenSource
+	regionCache  RegionCache
+	userAgent    string
+	requestHook  func(*http.Request)
+	responseHook func(*http.Response, error)
+}
+
+type Option func(*Client)
+
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		c.hc = hc
+	}
+}
+
+func WithBaseURL(u *url.URL) Option {
+	return func(c *Client) {
+		c.baseURL = u
+	}
+}
+
+// WithTokenSource configures the client to obtain bearer tokens from ts
+// before each request instead of requiring callers to pass one in. The
+// client calls ts.Token() on every request, so a refreshing token source
+// (such as one returned by an [oauth2.Config] backed by Tesla's
+// /oauth2/v3/token endpoint) will transparently refresh expired tokens.
+func WithTokenSource(ts oauth2.TokenSource) Option {
+	return func(c *Client) {
+		c.tokenSource = ts
+	}
+}
+
+// WithRegionCache configures the client to use cache to remember which
+// base URL a VIN's fleet region resolved to, instead of the in-memory
+// default. This lets callers persist the mapping across process
+// restarts.
+func WithRegionCache(cache RegionCache) Option {
+	return func(c *Client) {
+		c.regionCache = cache
+	}
+}
+
+// WithUserAgent overrides the User-Agent header sent on every request.
+// It defaults to "tesla-go/<version>".
+func WithUserAgent(ua string) Option {
+	return func(c *Client) {
+		c.userAgent = ua
+	}
+}
+
+// WithRequestHook registers hook to be called with every outbound
+// request just before it's sent, e.g. to add tracing spans or audit
+// logging. hook must not modify req's body.
+func WithRequestHook(hook func(*http.Request)) Option {
+	return func(c *Client) {
+		c.requestHook = hook
+	}
+}
+
+// WithResponseHook registers hook to be called with the result of every
+// request: the response (nil if the request failed outright) and the
+// transport-level error, if any. Unlike [WithRequestHook], this is the
+// place to record latency and status-code metrics; non-2xx responses
+// still reach the hook with a nil error.
+func WithResponseHook(hook func(*http.Response, error)) Option {
+	return func(c *Client) {
+		c.responseHook = hook
+	}
+}
+
+// New creates a new Tesla API client. Use options to supply different
+// HTTP clients, base URLs, and so on.
+func New(options ...Option) *Client {
+	c := &Client{
+		hc:          http.DefaultClient,
+		baseURL:     DefaultBaseURL,
+		regionCache: NewInMemoryRegionCache(),
+		userAgent:   defaultUserAgent,
+	}
+
+	for _, opt := range options {
+		opt(c)
+	}
+
+	return c
+}
+
+// ResolvedBaseURL returns the base URL the client would use for the
+// given VIN, taking any cached region failover into account. The second
+// return value is false if no region has been resolved yet, in which
+// case the client's default base URL would be used.
+func (c *Client) ResolvedBaseURL(vin string) (*url.URL, bool) {
+	if c.regionCache == nil {
+		return nil, false
+	}
+	return c.regionCache.Get(vin)
+}
+
+type responseWrapper[A any] struct {
+	Response A `json:"response"`
+}
+
+// do marshals body (if non-nil) as the JSON request body, sends it to
+// path (with query attached, if non-nil) on the base URL resolved for
+// vin, and returns the raw response body. On a 421 region mismatch it
+// parses the correct base URL out of the response, retries once against
+// it, and caches the result for vin so later calls skip the
+// wrong-region round trip.
+func (c *Client) do(ctx context.Context, method, path, vin string, query url.Values, body any) ([]byte, error) {
+	if vin != "" {
+		if err := validateVIN(vin); err != nil {
+			return nil, err
+		}
+	}
+
+	var reqBytes []byte
+	if body != nil {
+		var err error
+		reqBytes, err = json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize request body: %w", err)
+		}
+	}
+
+	base := c.baseURL
+	if cached, ok := c.ResolvedBaseURL(vin); ok {
+		base = cached
+	}
+
+	respBytes, status, header, err := c.doOnce(ctx, method, base, path, query, reqBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	if status == http.StatusMisdirectedRequest {
+		if altBase, parseErr := parseRegionRedirect(respBytes); parseErr == nil {
+			respBytes, status, header, err = c.doOnce(ctx, method, altBase, path, query, reqBytes)
+			if err != nil {
+				return nil, err
+			}
+
+			if status == http.StatusOK && c.regionCache != nil && vin != "" {
+				c.regionCache.Set(vin, altBase)
+			}
+		}
+	}
+
+	if status < 200 || status > 299 {
+		return nil, newAPIError(status, respBytes, header)
+	}
+
+	return respBytes, nil
+}
+
+func (c *Client) doOnce(ctx context.Context, method string, base *url.URL, path string, query url.Values, reqBytes []byte) ([]byte, int, http.Header, error) {
+	u := base.JoinPath(path)
+	if query != nil {
+		u.RawQuery = query.Encode()
+	}
+
+	var bodyReader io.Reader
+	if reqBytes != nil {
+		bodyReader = bytes.NewReader(reqBytes)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bodyReader)
+	if err != nil {
+		return nil, 0, nil, fmt.Errorf("failed to construct request: %w", err)
+	}
+
+	if reqBytes != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("User-Agent", c.userAgent)
+	if err := c.setAuthHeader(req); err != nil {
+		return nil, 0, nil, err
+	}
+
+	if c.requestHook != nil {
+		c.requestHook(req)
+	}
+
+	resp, err := c.hc.Do(req)
+	if err != nil {
+		if c.responseHook != nil {
+			c.responseHook(resp, err)
+		}
+		return nil, 0, nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	respBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		if c.responseHook != nil {
+			c.responseHook(resp, err)
+		}
+		return nil, 0, nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	// Give the hook a fresh reader over the bytes we already consumed, so
+	// it can inspect the body (e.g. for audit logging) without racing the
+	// read above.
+	resp.Body = io.NopCloser(bytes.NewReader(respBytes))
+	if c.responseHook != nil {
+		c.responseHook(resp, nil)
+	}
+
+	return respBytes, resp.StatusCode, resp.Header, nil
+}
+
+// setAuthHeader fetches a token from the configured token source and
+// attaches it to req as a bearer token. It returns an error if no token
+// source has been configured via [WithTokenSource].
+func (c *Client) setAuthHeader(req *http.Request) error {
+	if c.tokenSource == nil {
+		return fmt.Errorf("no token source configured, use WithTokenSource")
+	}
+
+	tok, err := c.tokenSource.Token()
+	if err != nil {
+		return fmt.Errorf("failed to obtain token: %w", err)
+	}
+
+	tok.SetAuthHeader(req)
+
+	return nil
+}
+
+// validateVIN rejects anything that isn't a syntactically valid VIN
+// before it's interpolated into a request path. The per-vehicle endpoint
+// methods build their paths with fmt.Sprintf("api/1/vehicles/%s/...", vin); since
+// url.URL.JoinPath resolves ".." segments, an unvalidated vin would let
+// a caller redirect the request to an arbitrary path on the same host.
+func validateVIN(vin string) error {
+	if len(vin) != 17 {
+		return fmt.Errorf("invalid VIN %q: must be 17 characters", vin)
+	}
+
+	for _, r := range vin {
+		isAlnum := (r >= 'A' && r <= 'Z') || (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9')
+		if !isAlnum {
+			return fmt.Errorf("invalid VIN %q: must be alphanumeric", vin)
+		}
+	}
+
+	return nil
+}
+
+// validateCommandName rejects anything that isn't a syntactically valid
+// legacy command name before [Client.PostVehicleCommand] interpolates it
+// into a request path, for the same reason [validateVIN] exists.
+func validateCommandName(name string) error {
+	if name == "" {
+		return fmt.Errorf("command name must not be empty")
+	}
+
+	for _, r := range name {
+		isValid := (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '_'
+		if !isValid {
+			return fmt.Errorf("invalid command name %q", name)
+		}
+	}
+
+	return nil
+}
+
+func decodeResponse[A any](respBytes []byte) (A, error) {
+	var respBody responseWrapper[A]
+
+	err := json.Unmarshal(respBytes, &respBody)
+	if err != nil {
+		var zero A
+		return zero, fmt.Errorf("failed to parse response body: %w", err)
+	}
+
+	return respBody.Response, nil
+}
@@ -0,0 +1,130 @@
+package tesla
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+
+	"golang.org/x/oauth2"
+)
+
+const testVIN = "5YJSA1E14FF000001"
+
+type stubTokenSource struct{}
+
+func (stubTokenSource) Token() (*oauth2.Token, error) {
+	return &oauth2.Token{AccessToken: "test-token"}, nil
+}
+
+// withRegion temporarily points regionBaseURLs[region] at base for the
+// duration of the test, restoring the original mapping on cleanup, so
+// a 421 redirect can be exercised against an httptest server instead of
+// a real fleet-api region host.
+func withRegion(t *testing.T, region, base string) {
+	t.Helper()
+
+	original, had := regionBaseURLs[region]
+	regionBaseURLs[region] = base
+	t.Cleanup(func() {
+		if had {
+			regionBaseURLs[region] = original
+		} else {
+			delete(regionBaseURLs, region)
+		}
+	})
+}
+
+func TestDoRetriesAndCachesOnRegionRedirect(t *testing.T) {
+	var euHits int32
+	eu := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&euHits, 1)
+		w.Write([]byte(`{"response":{"ok":true}}`))
+	}))
+	defer eu.Close()
+
+	withRegion(t, "eu", eu.URL)
+
+	var naHits int32
+	na := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&naHits, 1)
+		w.WriteHeader(http.StatusMisdirectedRequest)
+		w.Write([]byte(`{"error":"wrong_region","region":"eu"}`))
+	}))
+	defer na.Close()
+
+	naURL, err := url.Parse(na.URL)
+	if err != nil {
+		t.Fatalf("failed to parse test server URL: %v", err)
+	}
+
+	c := New(WithBaseURL(naURL), WithTokenSource(stubTokenSource{}))
+
+	respBytes, err := c.do(context.Background(), http.MethodGet, "api/1/vehicles/test", testVIN, nil, nil)
+	if err != nil {
+		t.Fatalf("do() returned error: %v", err)
+	}
+
+	if string(respBytes) != `{"response":{"ok":true}}` {
+		t.Fatalf("do() = %q, want %q", respBytes, `{"response":{"ok":true}}`)
+	}
+
+	if naHits != 1 {
+		t.Fatalf("na server hits = %d, want 1", naHits)
+	}
+	if euHits != 1 {
+		t.Fatalf("eu server hits = %d, want 1", euHits)
+	}
+
+	if cached, ok := c.ResolvedBaseURL(testVIN); !ok || cached.String() != eu.URL {
+		t.Fatalf("ResolvedBaseURL(%q) = (%v, %v), want (%s, true)", testVIN, cached, ok, eu.URL)
+	}
+
+	// A second call should go straight to the cached region and skip the
+	// wrong-region round trip entirely.
+	if _, err := c.do(context.Background(), http.MethodGet, "api/1/vehicles/test", testVIN, nil, nil); err != nil {
+		t.Fatalf("second do() returned error: %v", err)
+	}
+
+	if naHits != 1 {
+		t.Fatalf("na server hits after cached call = %d, want 1 (should not be hit again)", naHits)
+	}
+	if euHits != 2 {
+		t.Fatalf("eu server hits after cached call = %d, want 2", euHits)
+	}
+}
+
+func TestDoDoesNotCacheOnUnrecognizedRegion(t *testing.T) {
+	na := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusMisdirectedRequest)
+		w.Write([]byte(`{"error":"wrong_region","region":"mars"}`))
+	}))
+	defer na.Close()
+
+	naURL, err := url.Parse(na.URL)
+	if err != nil {
+		t.Fatalf("failed to parse test server URL: %v", err)
+	}
+
+	c := New(WithBaseURL(naURL), WithTokenSource(stubTokenSource{}))
+
+	_, err = c.do(context.Background(), http.MethodGet, "api/1/vehicles/test", testVIN, nil, nil)
+	if err == nil {
+		t.Fatal("do() = nil error, want an error from the unrecognized-region 421 response")
+	}
+
+	if _, ok := c.ResolvedBaseURL(testVIN); ok {
+		t.Fatal("ResolvedBaseURL() reported a cached region after an unrecognized-region redirect")
+	}
+}
+
+func TestDoRejectsInvalidVIN(t *testing.T) {
+	c := New(WithTokenSource(stubTokenSource{}))
+
+	_, err := c.do(context.Background(), http.MethodGet, "api/1/vehicles/test", "../../oauth2/v3/token", nil, nil)
+	if err == nil {
+		t.Fatal("do() = nil error, want an error for a path-traversal VIN")
+	}
+}
@@ -0,0 +1,239 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	tesla "github.com/DIMO-Network/tesla-go"
+)
+
+// Dispatcher sends vehicle commands through whichever transport a car
+// supports: the signed-command protocol for cars that report
+// VehicleCommandProtocolRequired, or the legacy REST commands
+// otherwise. It caches both the fleet status lookup and the derived
+// signed-command sessions, keyed by (VIN, Domain) since VCSEC and
+// Infotainment are independent controllers with independent key
+// exchanges and epoch/counter state, so repeated commands to the same
+// car and controller don't re-handshake.
+type Dispatcher struct {
+	client *tesla.Client
+
+	mu       sync.Mutex
+	sessions map[sessionKey]*Session
+	signed   map[string]bool
+}
+
+// sessionKey identifies one (VIN, Domain) signed-command session.
+type sessionKey struct {
+	vin    string
+	domain Domain
+}
+
+// NewDispatcher creates a Dispatcher that sends commands through
+// client.
+func NewDispatcher(client *tesla.Client) *Dispatcher {
+	return &Dispatcher{
+		client:   client,
+		sessions: make(map[sessionKey]*Session),
+		signed:   make(map[string]bool),
+	}
+}
+
+func (d *Dispatcher) usesSignedCommands(ctx context.Context, vin string) (bool, error) {
+	d.mu.Lock()
+	signed, ok := d.signed[vin]
+	d.mu.Unlock()
+	if ok {
+		return signed, nil
+	}
+
+	fs, err := d.client.GetFleetStatus(ctx, vin)
+	if err != nil {
+		return false, fmt.Errorf("failed to look up fleet status for %s: %w", vin, err)
+	}
+
+	d.mu.Lock()
+	d.signed[vin] = fs.VehicleCommandProtocolRequired
+	d.mu.Unlock()
+
+	return fs.VehicleCommandProtocolRequired, nil
+}
+
+func (d *Dispatcher) sessionFor(ctx context.Context, vin string, domain Domain) (*Session, error) {
+	key := sessionKey{vin: vin, domain: domain}
+
+	d.mu.Lock()
+	s, ok := d.sessions[key]
+	d.mu.Unlock()
+	if ok {
+		return s, nil
+	}
+
+	pub, err := d.client.GetVehiclePublicKey(ctx, vin)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch vehicle public key for %s: %w", vin, err)
+	}
+
+	s, err = NewSession(pub)
+	if err != nil {
+		return nil, fmt.Errorf("failed to establish session with %s: %w", vin, err)
+	}
+
+	if err := d.handshake(ctx, vin, domain, s); err != nil {
+		return nil, fmt.Errorf("failed to exchange session info with %s: %w", vin, err)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if existing, ok := d.sessions[key]; ok {
+		// Another call raced us and established a session for this
+		// (VIN, domain) first; use that one so every caller shares the
+		// same counter.
+		return existing, nil
+	}
+	d.sessions[key] = s
+
+	return s, nil
+}
+
+// handshake requests domain's current epoch, last-seen counter, and
+// clock from the car, and applies the reply to s, before s seals any
+// command. Without this, every sealed message would claim epoch zero
+// and an ExpiresAt of zero, which every real controller rejects as
+// already expired.
+func (d *Dispatcher) handshake(ctx context.Context, vin string, domain Domain, s *Session) error {
+	req := &RoutableMessage{
+		ToDomain:           domain,
+		SignerPublicKey:    s.PublicKey(),
+		RequestSessionInfo: true,
+	}
+
+	reqBytes, err := req.Marshal()
+	if err != nil {
+		return fmt.Errorf("failed to marshal session info request: %w", err)
+	}
+
+	respBytes, err := d.client.PostSignedCommand(ctx, vin, reqBytes)
+	if err != nil {
+		return err
+	}
+
+	resp, err := unmarshalRoutableMessage(respBytes)
+	if err != nil {
+		return fmt.Errorf("failed to parse session info reply: %w", err)
+	}
+
+	info, err := unmarshalSessionInfo(resp.Payload)
+	if err != nil {
+		return fmt.Errorf("failed to parse session info: %w", err)
+	}
+
+	s.applySessionInfo(info)
+
+	return nil
+}
+
+// Send dispatches the named command to the car with the given VIN,
+// transparently picking the signed-command protocol or the legacy REST
+// command based on that car's cached fleet status.
+func (d *Dispatcher) Send(ctx context.Context, vin string, name Name) error {
+	signed, err := d.usesSignedCommands(ctx, vin)
+	if err != nil {
+		return err
+	}
+
+	if signed {
+		return d.sendSigned(ctx, vin, name)
+	}
+
+	_, err = d.client.PostVehicleCommand(ctx, vin, string(name), nil)
+	return err
+}
+
+func (d *Dispatcher) sendSigned(ctx context.Context, vin string, name Name) error {
+	domain := domainFor(name)
+
+	session, err := d.sessionFor(ctx, vin, domain)
+	if err != nil {
+		return err
+	}
+
+	plaintext := encodeCommandPayload(name)
+
+	ciphertext, tag, nonce, counter, err := session.seal(plaintext, []byte{byte(domain)})
+	if err != nil {
+		return fmt.Errorf("failed to seal command payload: %w", err)
+	}
+
+	epoch, expiresAt := session.epochAndExpiry()
+
+	msg := &RoutableMessage{
+		ToDomain:  domain,
+		Payload:   ciphertext,
+		Nonce:     nonce,
+		Tag:       tag,
+		Counter:   counter,
+		Epoch:     epoch,
+		ExpiresAt: expiresAt,
+	}
+	if session.shouldAttachPublicKey() {
+		// First message of the session: let the car associate our
+		// ephemeral public key with the shared secret it already derived.
+		msg.SignerPublicKey = session.PublicKey()
+	}
+
+	reqBytes, err := msg.Marshal()
+	if err != nil {
+		return fmt.Errorf("failed to marshal routable message: %w", err)
+	}
+
+	respBytes, err := d.client.PostSignedCommand(ctx, vin, reqBytes)
+	if err != nil {
+		return err
+	}
+
+	if _, err := unmarshalRoutableMessage(respBytes); err != nil {
+		return fmt.Errorf("failed to parse vehicle response: %w", err)
+	}
+
+	return nil
+}
+
+// Lock locks the car's doors.
+func (d *Dispatcher) Lock(ctx context.Context, vin string) error { return d.Send(ctx, vin, NameLock) }
+
+// Unlock unlocks the car's doors.
+func (d *Dispatcher) Unlock(ctx context.Context, vin string) error {
+	return d.Send(ctx, vin, NameUnlock)
+}
+
+// HonkHorn honks the car's horn once.
+func (d *Dispatcher) HonkHorn(ctx context.Context, vin string) error {
+	return d.Send(ctx, vin, NameHonkHorn)
+}
+
+// FlashLights flashes the car's lights once.
+func (d *Dispatcher) FlashLights(ctx context.Context, vin string) error {
+	return d.Send(ctx, vin, NameFlashLights)
+}
+
+// ChargeStart starts charging, if the car is plugged in.
+func (d *Dispatcher) ChargeStart(ctx context.Context, vin string) error {
+	return d.Send(ctx, vin, NameChargeStart)
+}
+
+// ChargeStop stops charging.
+func (d *Dispatcher) ChargeStop(ctx context.Context, vin string) error {
+	return d.Send(ctx, vin, NameChargeStop)
+}
+
+// ClimateOn turns on the car's HVAC system.
+func (d *Dispatcher) ClimateOn(ctx context.Context, vin string) error {
+	return d.Send(ctx, vin, NameClimateOn)
+}
+
+// ClimateOff turns off the car's HVAC system.
+func (d *Dispatcher) ClimateOff(ctx context.Context, vin string) error {
+	return d.Send(ctx, vin, NameClimateOff)
+}
@@ -0,0 +1,198 @@
+package commands
+
+import (
+	"context"
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+
+	tesla "github.com/DIMO-Network/tesla-go"
+	"golang.org/x/oauth2"
+)
+
+const testVIN = "5YJSA1E14FF000001"
+
+type stubTokenSource struct{}
+
+func (stubTokenSource) Token() (*oauth2.Token, error) {
+	return &oauth2.Token{AccessToken: "test-token"}, nil
+}
+
+func writeJSONResponse(w http.ResponseWriter, response any) {
+	_ = json.NewEncoder(w).Encode(map[string]any{"response": response})
+}
+
+func TestSendUsesLegacyPathWhenProtocolNotRequired(t *testing.T) {
+	var signedCommandHits int32
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/1/vehicles/fleet_status", func(w http.ResponseWriter, r *http.Request) {
+		writeJSONResponse(w, map[string]any{
+			"key_paired_vins": []string{testVIN},
+			"vehicle_info": map[string]any{
+				testVIN: map[string]any{"vehicle_command_protocol_required": false},
+			},
+		})
+	})
+	mux.HandleFunc(fmt.Sprintf("/api/1/vehicles/%s/command/honk_horn", testVIN), func(w http.ResponseWriter, r *http.Request) {
+		writeJSONResponse(w, map[string]any{})
+	})
+	mux.HandleFunc(fmt.Sprintf("/api/1/vehicles/%s/signed_command", testVIN), func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&signedCommandHits, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	d := newTestDispatcher(t, server.URL)
+
+	if err := d.HonkHorn(context.Background(), testVIN); err != nil {
+		t.Fatalf("HonkHorn() returned error: %v", err)
+	}
+
+	if signedCommandHits != 0 {
+		t.Fatalf("signed_command was hit %d times, want 0 for a car that doesn't require it", signedCommandHits)
+	}
+}
+
+func TestSendUsesSignedPathAndHandshakesOncePerDomain(t *testing.T) {
+	vehicleKey, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate test vehicle key: %v", err)
+	}
+
+	var handshakeHits, sealedCommandHits int32
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/1/vehicles/fleet_status", func(w http.ResponseWriter, r *http.Request) {
+		writeJSONResponse(w, map[string]any{
+			"key_paired_vins": []string{testVIN},
+			"vehicle_info": map[string]any{
+				testVIN: map[string]any{"vehicle_command_protocol_required": true},
+			},
+		})
+	})
+	mux.HandleFunc(fmt.Sprintf("/api/1/vehicles/%s/signed_command", testVIN), func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			writeJSONResponse(w, map[string]any{"public_key": vehicleKey.PublicKey().Bytes()})
+			return
+		}
+
+		var body struct {
+			RoutableMessage []byte `json:"routable_message"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("failed to decode request body: %v", err)
+		}
+
+		msg, err := unmarshalRoutableMessage(body.RoutableMessage)
+		if err != nil {
+			t.Fatalf("failed to parse request routable message: %v", err)
+		}
+
+		if isSessionInfoRequest(t, body.RoutableMessage) {
+			atomic.AddInt32(&handshakeHits, 1)
+			reply := buildFakeSessionInfoReply(msg.ToDomain, vehicleKey.PublicKey().Bytes(), []byte("0123456789012345"), 0, 1000)
+			writeJSONResponse(w, map[string]any{"routable_message": reply})
+			return
+		}
+
+		atomic.AddInt32(&sealedCommandHits, 1)
+		ack := (&RoutableMessage{ToDomain: msg.ToDomain}).mustMarshal(t)
+		writeJSONResponse(w, map[string]any{"routable_message": ack})
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	d := newTestDispatcher(t, server.URL)
+
+	// Lock and Unlock both target VCSEC: one handshake, two sealed
+	// commands.
+	if err := d.Lock(context.Background(), testVIN); err != nil {
+		t.Fatalf("Lock() returned error: %v", err)
+	}
+	if err := d.Unlock(context.Background(), testVIN); err != nil {
+		t.Fatalf("Unlock() returned error: %v", err)
+	}
+
+	if handshakeHits != 1 {
+		t.Fatalf("handshake hits = %d, want 1 (session should be reused across Lock/Unlock)", handshakeHits)
+	}
+	if sealedCommandHits != 2 {
+		t.Fatalf("sealed command hits = %d, want 2", sealedCommandHits)
+	}
+
+	// ChargeStart targets Infotainment, a different controller, so it
+	// must handshake again even though VCSEC already has a session.
+	if err := d.ChargeStart(context.Background(), testVIN); err != nil {
+		t.Fatalf("ChargeStart() returned error: %v", err)
+	}
+
+	if handshakeHits != 2 {
+		t.Fatalf("handshake hits after ChargeStart = %d, want 2 (VCSEC and Infotainment need separate sessions)", handshakeHits)
+	}
+	if sealedCommandHits != 3 {
+		t.Fatalf("sealed command hits after ChargeStart = %d, want 3", sealedCommandHits)
+	}
+}
+
+func newTestDispatcher(t *testing.T, baseURL string) *Dispatcher {
+	t.Helper()
+
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		t.Fatalf("failed to parse test server URL: %v", err)
+	}
+
+	client := tesla.New(tesla.WithBaseURL(u), tesla.WithTokenSource(stubTokenSource{}))
+	return NewDispatcher(client)
+}
+
+// isSessionInfoRequest reports whether raw (an already-marshaled
+// RoutableMessage) is a session-info request rather than a sealed
+// command, by checking which signature_data variant it carries.
+func isSessionInfoRequest(t *testing.T, raw []byte) bool {
+	t.Helper()
+
+	fields, err := parseProtoFields(raw)
+	if err != nil {
+		t.Fatalf("failed to parse routable message: %v", err)
+	}
+
+	for _, f := range fields {
+		if f.num != fieldRoutableSignatureData {
+			continue
+		}
+
+		sigFields, err := parseProtoFields(f.bytesData)
+		if err != nil {
+			t.Fatalf("failed to parse signature_data: %v", err)
+		}
+
+		for _, sf := range sigFields {
+			if sf.num == fieldSignatureSessionInfoRequest {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (m *RoutableMessage) mustMarshal(t *testing.T) []byte {
+	t.Helper()
+
+	b, err := m.Marshal()
+	if err != nil {
+		t.Fatalf("failed to marshal routable message: %v", err)
+	}
+	return b
+}
@@ -0,0 +1,26 @@
+// Package commands sends signed vehicle commands to cars that report
+// [tesla.FleetStatus.VehicleCommandProtocolRequired], falling back to
+// the legacy REST commands otherwise.
+//
+// It implements the parts of Tesla's [vehicle-command] protocol needed
+// to authenticate a command: an ECDH (NIST P-256) key exchange with the
+// car, HKDF session-key derivation, a session-info handshake to learn
+// the target controller's current epoch/counter/clock, and AES-GCM
+// sealing of the command payload. RoutableMessage and SignatureData in
+// [message.go] are encoded/decoded as genuine protobuf wire bytes (see
+// wire.go), with field numbers hand-transcribed from the public
+// universal_message.proto/signatures.proto definitions rather than
+// generated from them, since this tree has no network access to vendor
+// [github.com/teslamotors/vehicle-command]. The inner, domain-specific
+// command payload (VCSEC.UnsignedMessage/CarServer.Action) is still a
+// single-field placeholder rather than those full schemas, and the
+// session-info reply's authenticity isn't verified (the real protocol
+// signs it with a key derived from the handshake); swap in the
+// generated types and that verification for complete interoperability.
+//
+// VCSEC and Infotainment are independent controllers with independent
+// key exchanges and epoch/counter state, so Dispatcher keeps a separate
+// Session per (VIN, Domain) rather than one session per VIN.
+//
+// [vehicle-command]: https://github.com/teslamotors/vehicle-command
+package commands
@@ -0,0 +1,303 @@
+package commands
+
+import "fmt"
+
+// Field numbers below are transcribed from the public
+// universal_message.proto and signatures.proto definitions in
+// [vehicle-command]; this package encodes/decodes them by hand with the
+// wire helpers in wire.go rather than by vendoring the generated Go
+// types, since this tree has no network access to fetch that module.
+// The inner, domain-specific command payload (VCSEC.UnsignedMessage /
+// CarServer.Action) is still a minimal single-field placeholder — see
+// [encodeCommandPayload] — since those schemas are large enough that
+// hand-transcribing them accurately is out of scope here; swap in the
+// generated vehicle-command types for full interoperability.
+//
+// A session-info request/reply (see [sessionInfo] and
+// [Dispatcher.handshake]) is exchanged before a [Session]'s first
+// command, so Epoch/ExpiresAt reflect the target controller's actual
+// clock instead of the zero values of a never-handshaken session.
+//
+// [vehicle-command]: https://github.com/teslamotors/vehicle-command
+const (
+	// UniversalMessage.RoutableMessage fields.
+	fieldRoutableToDestination   = 1
+	fieldRoutableFromDestination = 2
+	fieldRoutablePayload         = 3 // protobuf_message_as_bytes
+	fieldRoutableSignatureData   = 6
+
+	// UniversalMessage.Destination fields (oneof sub_destination).
+	fieldDestinationDomain = 1
+
+	// Signatures.SignatureData fields.
+	fieldSignatureSignerIdentity     = 1
+	fieldSignatureSessionInfoRequest = 5
+	fieldSignatureAESGCMPersonalized = 6
+
+	// Signatures.KeyIdentity fields (oneof identity_type).
+	fieldKeyIdentityPublicKey = 1
+
+	// Signatures.AES_GCM_Personalized_Signature_Data fields.
+	fieldAESGCMEpoch     = 1
+	fieldAESGCMNonce     = 2
+	fieldAESGCMCounter   = 3
+	fieldAESGCMExpiresAt = 4
+	fieldAESGCMTag       = 5
+
+	// UniversalMessage.SessionInfo fields, carried in the payload of a
+	// car's reply to a session-info request.
+	fieldSessionInfoPublicKey = 1
+	fieldSessionInfoEpoch     = 2
+	fieldSessionInfoCounter   = 3
+	fieldSessionInfoClockTime = 4
+)
+
+// Domain identifies which of the car's controllers a command is routed
+// to, mirroring UniversalMessage.Domain in the real protocol.
+type Domain int
+
+const (
+	// DomainVehicleSecurity is the VCSEC controller: locks, the horn,
+	// lights, and other body-control functions.
+	DomainVehicleSecurity Domain = 2
+	// DomainInfotainment is the car's infotainment computer: climate,
+	// charging, media, and anything else exposed over the legacy REST
+	// commands.
+	DomainInfotainment Domain = 3
+)
+
+// Name is a signed-command payload identifier. It intentionally reuses
+// the same strings as the legacy REST command endpoints so a
+// [Dispatcher] can fall back to them unchanged.
+type Name string
+
+const (
+	NameLock        Name = "door_lock"
+	NameUnlock      Name = "door_unlock"
+	NameHonkHorn    Name = "honk_horn"
+	NameFlashLights Name = "flash_lights"
+	NameChargeStart Name = "charge_start"
+	NameChargeStop  Name = "charge_stop"
+	NameClimateOn   Name = "auto_conditioning_start"
+	NameClimateOff  Name = "auto_conditioning_stop"
+)
+
+// domainFor reports which controller handles a given command.
+func domainFor(name Name) Domain {
+	switch name {
+	case NameLock, NameUnlock, NameHonkHorn, NameFlashLights:
+		return DomainVehicleSecurity
+	default:
+		return DomainInfotainment
+	}
+}
+
+// encodeCommandPayload builds the plaintext message sealed inside a
+// RoutableMessage's payload. See the package-level field-number comment
+// for why this is a placeholder rather than a genuine
+// VCSEC.UnsignedMessage/CarServer.Action encoding.
+func encodeCommandPayload(name Name) []byte {
+	return appendBytesField(nil, 1, []byte(name))
+}
+
+// aesGCMPersonalizedSignature is Signatures.AES_GCM_Personalized_Signature_Data:
+// the AEAD parameters the car needs to verify and decrypt a command
+// sealed under a personalized (i.e. not pre-shared) session key.
+type aesGCMPersonalizedSignature struct {
+	Epoch     []byte
+	Nonce     []byte
+	Counter   uint32
+	ExpiresAt uint32
+	Tag       []byte
+}
+
+func (s aesGCMPersonalizedSignature) marshal() []byte {
+	var b []byte
+	b = appendBytesField(b, fieldAESGCMEpoch, s.Epoch)
+	b = appendBytesField(b, fieldAESGCMNonce, s.Nonce)
+	b = appendVarintField(b, fieldAESGCMCounter, uint64(s.Counter))
+	b = appendVarintField(b, fieldAESGCMExpiresAt, uint64(s.ExpiresAt))
+	b = appendBytesField(b, fieldAESGCMTag, s.Tag)
+	return b
+}
+
+// signatureData is Signatures.SignatureData. SignerPublicKey is only
+// set on the first message of a session, to let the car associate our
+// ephemeral key with the shared secret it already derived; afterwards
+// it identifies us implicitly via the session the counter belongs to.
+// SessionInfoRequest and AESGCMPersonalized are mutually exclusive, one
+// per message: a session starts with a session-info request, then
+// every command after it carries an AES-GCM signature.
+type signatureData struct {
+	SignerPublicKey    []byte
+	SessionInfoRequest []byte // our public key; requests the target's current epoch/counter/clock
+	AESGCMPersonalized *aesGCMPersonalizedSignature
+}
+
+func (s signatureData) marshal() []byte {
+	var b []byte
+	if len(s.SignerPublicKey) != 0 {
+		keyIdentity := appendBytesField(nil, fieldKeyIdentityPublicKey, s.SignerPublicKey)
+		b = appendMessageField(b, fieldSignatureSignerIdentity, keyIdentity)
+	}
+
+	switch {
+	case len(s.SessionInfoRequest) != 0:
+		keyIdentity := appendBytesField(nil, fieldKeyIdentityPublicKey, s.SessionInfoRequest)
+		b = appendMessageField(b, fieldSignatureSessionInfoRequest, keyIdentity)
+	case s.AESGCMPersonalized != nil:
+		b = appendMessageField(b, fieldSignatureAESGCMPersonalized, s.AESGCMPersonalized.marshal())
+	}
+
+	return b
+}
+
+// sessionInfo is UniversalMessage.SessionInfo: the target controller's
+// current epoch, last-seen counter, and clock, carried in the payload
+// of its reply to a session-info request. A [Session] must fetch this
+// once, per controller domain, before sealing its first command, or
+// every AES-GCM signature it produces would claim epoch zero and an
+// already-expired ExpiresAt (see [Session.applySessionInfo]).
+//
+// This package doesn't verify the reply's authenticity — the real
+// protocol signs it with a key derived from the handshake, which is a
+// further piece of the protocol not reimplemented here.
+type sessionInfo struct {
+	PublicKey []byte
+	Epoch     []byte
+	Counter   uint32
+	ClockTime uint32
+}
+
+// unmarshalSessionInfo decodes a UniversalMessage.SessionInfo from the
+// payload of a session-info-request reply.
+func unmarshalSessionInfo(b []byte) (*sessionInfo, error) {
+	fields, err := parseProtoFields(b)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &sessionInfo{}
+
+	for _, f := range fields {
+		switch f.num {
+		case fieldSessionInfoPublicKey:
+			info.PublicKey = f.bytesData
+		case fieldSessionInfoEpoch:
+			info.Epoch = f.bytesData
+		case fieldSessionInfoCounter:
+			info.Counter = uint32(f.varint)
+		case fieldSessionInfoClockTime:
+			info.ClockTime = uint32(f.varint)
+		}
+	}
+
+	return info, nil
+}
+
+// RoutableMessage is UniversalMessage.RoutableMessage: the envelope sent
+// to and received from the car's signed_command endpoint. Marshal/parse
+// produce and consume genuine protobuf wire bytes (see wire.go), not a
+// JSON stand-in.
+type RoutableMessage struct {
+	ToDomain        Domain
+	SignerPublicKey []byte // only set on the first message of a session
+	Payload         []byte // AES-GCM ciphertext, tag excluded; or a sessionInfo reply
+	Nonce           []byte
+	Tag             []byte
+	Counter         uint32
+	Epoch           []byte
+	ExpiresAt       uint32
+
+	// RequestSessionInfo marks this message as a session-info request
+	// rather than a sealed command: Marshal encodes SignerPublicKey as
+	// the SessionInfoRequest signature variant instead of an AES-GCM
+	// signature, and Payload/Nonce/Tag/Counter/Epoch/ExpiresAt are
+	// ignored.
+	RequestSessionInfo bool
+}
+
+// Marshal encodes m as a UniversalMessage.RoutableMessage.
+func (m *RoutableMessage) Marshal() ([]byte, error) {
+	destination := appendVarintField(nil, fieldDestinationDomain, uint64(m.ToDomain))
+
+	var sig signatureData
+	if m.RequestSessionInfo {
+		sig = signatureData{SessionInfoRequest: m.SignerPublicKey}
+	} else {
+		sig = signatureData{
+			SignerPublicKey: m.SignerPublicKey,
+			AESGCMPersonalized: &aesGCMPersonalizedSignature{
+				Epoch:     m.Epoch,
+				Nonce:     m.Nonce,
+				Counter:   m.Counter,
+				ExpiresAt: m.ExpiresAt,
+				Tag:       m.Tag,
+			},
+		}
+	}
+
+	var b []byte
+	b = appendMessageField(b, fieldRoutableToDestination, destination)
+	if !m.RequestSessionInfo {
+		b = appendBytesField(b, fieldRoutablePayload, m.Payload)
+	}
+	b = appendMessageField(b, fieldRoutableSignatureData, sig.marshal())
+
+	return b, nil
+}
+
+// unmarshalRoutableMessage decodes a UniversalMessage.RoutableMessage,
+// pulling out only the fields this package reads back out of the car's
+// response (the destination domain and the payload/signature bytes,
+// when present).
+func unmarshalRoutableMessage(b []byte) (*RoutableMessage, error) {
+	fields, err := parseProtoFields(b)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &RoutableMessage{}
+
+	for _, f := range fields {
+		switch f.num {
+		case fieldRoutableToDestination:
+			destFields, err := parseProtoFields(f.bytesData)
+			if err != nil {
+				return nil, fmt.Errorf("invalid to_destination: %w", err)
+			}
+			for _, df := range destFields {
+				if df.num == fieldDestinationDomain {
+					m.ToDomain = Domain(df.varint)
+				}
+			}
+		case fieldRoutablePayload:
+			m.Payload = f.bytesData
+		case fieldRoutableSignatureData:
+			sigFields, err := parseProtoFields(f.bytesData)
+			if err != nil {
+				return nil, fmt.Errorf("invalid signature_data: %w", err)
+			}
+			for _, sf := range sigFields {
+				if sf.num == fieldSignatureAESGCMPersonalized {
+					aesFields, err := parseProtoFields(sf.bytesData)
+					if err != nil {
+						return nil, fmt.Errorf("invalid signature_data: %w", err)
+					}
+					for _, af := range aesFields {
+						switch af.num {
+						case fieldAESGCMNonce:
+							m.Nonce = af.bytesData
+						case fieldAESGCMTag:
+							m.Tag = af.bytesData
+						case fieldAESGCMCounter:
+							m.Counter = uint32(af.varint)
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return m, nil
+}
@@ -0,0 +1,141 @@
+package commands
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestRoutableMessageMarshalUnmarshalRoundTrip(t *testing.T) {
+	msg := &RoutableMessage{
+		ToDomain:        DomainVehicleSecurity,
+		SignerPublicKey: []byte("ephemeral-pub-key"),
+		Payload:         []byte("ciphertext"),
+		Nonce:           []byte("nonce1234567"),
+		Tag:             []byte("tag1234567890123"),
+		Counter:         3,
+		Epoch:           []byte("0123456789012345"),
+		ExpiresAt:       1234,
+	}
+
+	b, err := msg.Marshal()
+	if err != nil {
+		t.Fatalf("Marshal() returned error: %v", err)
+	}
+
+	got, err := unmarshalRoutableMessage(b)
+	if err != nil {
+		t.Fatalf("unmarshalRoutableMessage() returned error: %v", err)
+	}
+
+	if got.ToDomain != msg.ToDomain {
+		t.Errorf("ToDomain = %v, want %v", got.ToDomain, msg.ToDomain)
+	}
+	if !bytes.Equal(got.Payload, msg.Payload) {
+		t.Errorf("Payload = %q, want %q", got.Payload, msg.Payload)
+	}
+	if !bytes.Equal(got.Nonce, msg.Nonce) {
+		t.Errorf("Nonce = %q, want %q", got.Nonce, msg.Nonce)
+	}
+	if !bytes.Equal(got.Tag, msg.Tag) {
+		t.Errorf("Tag = %q, want %q", got.Tag, msg.Tag)
+	}
+	if got.Counter != msg.Counter {
+		t.Errorf("Counter = %d, want %d", got.Counter, msg.Counter)
+	}
+}
+
+func TestRoutableMessageSessionInfoRequestMarshal(t *testing.T) {
+	msg := &RoutableMessage{
+		ToDomain:           DomainInfotainment,
+		SignerPublicKey:    []byte("ephemeral-pub-key"),
+		RequestSessionInfo: true,
+	}
+
+	b, err := msg.Marshal()
+	if err != nil {
+		t.Fatalf("Marshal() returned error: %v", err)
+	}
+
+	fields, err := parseProtoFields(b)
+	if err != nil {
+		t.Fatalf("parseProtoFields() returned error: %v", err)
+	}
+
+	var sawSignatureData bool
+	for _, f := range fields {
+		if f.num != fieldRoutableSignatureData {
+			continue
+		}
+		sawSignatureData = true
+
+		sigFields, err := parseProtoFields(f.bytesData)
+		if err != nil {
+			t.Fatalf("parseProtoFields(signature_data) returned error: %v", err)
+		}
+
+		var sawSessionInfoRequest, sawAESGCM bool
+		for _, sf := range sigFields {
+			switch sf.num {
+			case fieldSignatureSessionInfoRequest:
+				sawSessionInfoRequest = true
+			case fieldSignatureAESGCMPersonalized:
+				sawAESGCM = true
+			}
+		}
+		if !sawSessionInfoRequest {
+			t.Error("signature_data has no session_info_request field")
+		}
+		if sawAESGCM {
+			t.Error("signature_data has an aes_gcm_personalized field, want none for a session-info request")
+		}
+	}
+	if !sawSignatureData {
+		t.Fatal("marshaled message has no signature_data field")
+	}
+}
+
+func TestUnmarshalSessionInfo(t *testing.T) {
+	var b []byte
+	b = appendBytesField(b, fieldSessionInfoPublicKey, []byte("vehicle-pub-key"))
+	b = appendBytesField(b, fieldSessionInfoEpoch, []byte("0123456789012345"))
+	b = appendVarintField(b, fieldSessionInfoCounter, 7)
+	b = appendVarintField(b, fieldSessionInfoClockTime, 99999)
+
+	info, err := unmarshalSessionInfo(b)
+	if err != nil {
+		t.Fatalf("unmarshalSessionInfo() returned error: %v", err)
+	}
+
+	if !bytes.Equal(info.PublicKey, []byte("vehicle-pub-key")) {
+		t.Errorf("PublicKey = %q, want %q", info.PublicKey, "vehicle-pub-key")
+	}
+	if !bytes.Equal(info.Epoch, []byte("0123456789012345")) {
+		t.Errorf("Epoch = %q, want %q", info.Epoch, "0123456789012345")
+	}
+	if info.Counter != 7 {
+		t.Errorf("Counter = %d, want 7", info.Counter)
+	}
+	if info.ClockTime != 99999 {
+		t.Errorf("ClockTime = %d, want 99999", info.ClockTime)
+	}
+}
+
+// buildFakeSessionInfoReply builds the bytes of a RoutableMessage whose
+// payload is a sessionInfo message, as a real controller would reply to
+// a session-info request. Used by dispatcher_test.go to fake that
+// reply over HTTP without needing the controller's real signing key.
+func buildFakeSessionInfoReply(domain Domain, pub, epoch []byte, counter, clockTime uint32) []byte {
+	destination := appendVarintField(nil, fieldDestinationDomain, uint64(domain))
+
+	var payload []byte
+	payload = appendBytesField(payload, fieldSessionInfoPublicKey, pub)
+	payload = appendBytesField(payload, fieldSessionInfoEpoch, epoch)
+	payload = appendVarintField(payload, fieldSessionInfoCounter, uint64(counter))
+	payload = appendVarintField(payload, fieldSessionInfoClockTime, uint64(clockTime))
+
+	var b []byte
+	b = appendMessageField(b, fieldRoutableToDestination, destination)
+	b = appendBytesField(b, fieldRoutablePayload, payload)
+
+	return b
+}
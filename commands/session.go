@@ -0,0 +1,169 @@
+package commands
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+// commandTTL is how long a sealed command is valid for, measured
+// against the target controller's clock. It matches the short expiry
+// window the real protocol uses to bound replay of a captured command.
+const commandTTL = 5 * time.Second
+
+// sessionKeyInfo is the HKDF info parameter used to derive the AES-128
+// session key from the ECDH shared secret, matching the label used by
+// the real vehicle-command protocol.
+var sessionKeyInfo = []byte("session info")
+
+// Session holds the key material used to authenticate signed commands
+// sent to a single vehicle: our ephemeral ECDH key pair, the car's
+// public key, and the AES session key derived from their shared secret.
+//
+// A Session is single-vehicle-and-domain and single-use for the
+// lifetime of the process: VCSEC and Infotainment are independent
+// controllers, so callers need one Session per (VIN, Domain) pair and
+// should reuse it across commands rather than re-deriving it each time.
+// A freshly constructed Session has no epoch/clock of its own; call
+// [Session.applySessionInfo] with the result of a session-info
+// handshake before sealing its first command. Session's methods are
+// safe for concurrent use: they serialize access to the AEAD counter
+// and the handshake state so concurrent commands against the same
+// session never reuse or skip a counter value.
+type Session struct {
+	privateKey       *ecdh.PrivateKey
+	vehiclePublicKey *ecdh.PublicKey
+	sessionKey       []byte
+
+	mu                   sync.Mutex
+	counter              uint32
+	keySent              bool
+	epoch                []byte
+	clockTimeAtHandshake uint32
+	handshakeAt          time.Time
+}
+
+// NewSession generates a fresh ephemeral key pair, computes the ECDH
+// shared secret with the car's public key (as returned by
+// [tesla.Client.GetVehiclePublicKey]), and derives the AES session key
+// used to seal subsequent commands.
+func NewSession(vehiclePublicKey []byte) (*Session, error) {
+	curve := ecdh.P256()
+
+	priv, err := curve.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate ephemeral key pair: %w", err)
+	}
+
+	pub, err := curve.NewPublicKey(vehiclePublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid vehicle public key: %w", err)
+	}
+
+	shared, err := priv.ECDH(pub)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute ECDH shared secret: %w", err)
+	}
+
+	sessionKey := make([]byte, 16) // AES-128
+	if _, err := io.ReadFull(hkdf.New(sha256.New, shared, nil, sessionKeyInfo), sessionKey); err != nil {
+		return nil, fmt.Errorf("failed to derive session key: %w", err)
+	}
+
+	return &Session{
+		privateKey:       priv,
+		vehiclePublicKey: pub,
+		sessionKey:       sessionKey,
+	}, nil
+}
+
+// PublicKey returns our ephemeral public key, to be sent to the car on
+// the first message of a session so it can derive the same shared
+// secret.
+func (s *Session) PublicKey() []byte {
+	return s.privateKey.PublicKey().Bytes()
+}
+
+// applySessionInfo records the target controller's current epoch and
+// last-seen counter from a session-info handshake reply, and starts
+// this Session's clock reference so later calls to [Session.epochAndExpiry]
+// track the controller's clock rather than claiming epoch zero and an
+// already-expired ExpiresAt.
+func (s *Session) applySessionInfo(info *sessionInfo) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.epoch = info.Epoch
+	s.counter = info.Counter
+	s.clockTimeAtHandshake = info.ClockTime
+	s.handshakeAt = time.Now()
+}
+
+// shouldAttachPublicKey reports whether this call is the first to seal
+// a command on this session, in which case the caller must attach our
+// ephemeral public key so the controller can associate it with the
+// shared secret it already derived. It's safe to call concurrently:
+// exactly one caller ever sees true.
+func (s *Session) shouldAttachPublicKey() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.keySent {
+		return false
+	}
+	s.keySent = true
+	return true
+}
+
+// epochAndExpiry returns the epoch from the session's last handshake
+// and an ExpiresAt timestamp, on the target controller's clock, valid
+// for [commandTTL] from now.
+func (s *Session) epochAndExpiry() (epoch []byte, expiresAt uint32) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	elapsed := uint32(time.Since(s.handshakeAt).Seconds())
+	return s.epoch, s.clockTimeAtHandshake + elapsed + uint32(commandTTL.Seconds())
+}
+
+// seal encrypts plaintext with the session's AES-128-GCM key under a
+// freshly generated random nonce, authenticating associatedData
+// alongside it, and increments the command counter, returning the new
+// value for the caller to send with the command. The GCM
+// authentication tag is returned separately from the ciphertext, since
+// the wire protocol (see [signatureData]) carries them in separate
+// fields rather than concatenated.
+func (s *Session) seal(plaintext, associatedData []byte) (ciphertext, tag, nonce []byte, counter uint32, err error) {
+	block, err := aes.NewCipher(s.sessionKey)
+	if err != nil {
+		return nil, nil, nil, 0, fmt.Errorf("failed to construct AES cipher: %w", err)
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, nil, nil, 0, fmt.Errorf("failed to construct AES-GCM: %w", err)
+	}
+
+	nonce = make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, nil, nil, 0, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	s.mu.Lock()
+	s.counter++
+	counter = s.counter
+	s.mu.Unlock()
+
+	sealed := gcm.Seal(nil, nonce, plaintext, associatedData)
+	tagSize := gcm.Overhead()
+
+	return sealed[:len(sealed)-tagSize], sealed[len(sealed)-tagSize:], nonce, counter, nil
+}
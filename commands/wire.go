@@ -0,0 +1,112 @@
+package commands
+
+import "fmt"
+
+// Minimal protobuf wire-format helpers (varint + length-delimited
+// encoding per the [protobuf encoding spec]), used to build the actual
+// byte layout the vehicle-command protocol expects instead of an ad-hoc
+// JSON envelope. This is not a generated-code replacement for
+// github.com/teslamotors/vehicle-command/pkg/protocol/protobuf — it only
+// implements the field subset this package sends and reads.
+//
+// [protobuf encoding spec]: https://protobuf.dev/programming-guides/encoding/
+const (
+	wireVarint = 0
+	wireBytes  = 2
+)
+
+func appendVarint(b []byte, v uint64) []byte {
+	for v >= 0x80 {
+		b = append(b, byte(v)|0x80)
+		v >>= 7
+	}
+	return append(b, byte(v))
+}
+
+func appendTag(b []byte, fieldNum, wireType int) []byte {
+	return appendVarint(b, uint64(fieldNum)<<3|uint64(wireType))
+}
+
+func appendVarintField(b []byte, fieldNum int, v uint64) []byte {
+	b = appendTag(b, fieldNum, wireVarint)
+	return appendVarint(b, v)
+}
+
+func appendBytesField(b []byte, fieldNum int, data []byte) []byte {
+	b = appendTag(b, fieldNum, wireBytes)
+	b = appendVarint(b, uint64(len(data)))
+	return append(b, data...)
+}
+
+// appendMessageField encodes msg (itself already-encoded protobuf bytes)
+// as a length-delimited submessage field.
+func appendMessageField(b []byte, fieldNum int, msg []byte) []byte {
+	return appendBytesField(b, fieldNum, msg)
+}
+
+// protoField is one decoded top-level field: its number and raw bytes
+// (for wireBytes) or value (for wireVarint, stored as the raw varint).
+type protoField struct {
+	num       int
+	wireType  int
+	varint    uint64
+	bytesData []byte
+}
+
+// parseProtoFields walks b and returns every top-level field it finds,
+// in wire order. It's deliberately permissive about field numbers:
+// unknown ones are still returned, so callers can pick out only the
+// fields they care about. Wire types other than varint and
+// length-delimited are rejected with an error.
+func parseProtoFields(b []byte) ([]protoField, error) {
+	var fields []protoField
+
+	for len(b) > 0 {
+		tag, n := readVarint(b)
+		if n == 0 {
+			return nil, fmt.Errorf("invalid tag")
+		}
+		b = b[n:]
+
+		fieldNum := int(tag >> 3)
+		wireType := int(tag & 0x7)
+
+		switch wireType {
+		case wireVarint:
+			v, n := readVarint(b)
+			if n == 0 {
+				return nil, fmt.Errorf("invalid varint for field %d", fieldNum)
+			}
+			b = b[n:]
+			fields = append(fields, protoField{num: fieldNum, wireType: wireType, varint: v})
+		case wireBytes:
+			length, n := readVarint(b)
+			if n == 0 {
+				return nil, fmt.Errorf("invalid length for field %d", fieldNum)
+			}
+			b = b[n:]
+			if uint64(len(b)) < length {
+				return nil, fmt.Errorf("truncated data for field %d", fieldNum)
+			}
+			fields = append(fields, protoField{num: fieldNum, wireType: wireType, bytesData: b[:length]})
+			b = b[length:]
+		default:
+			return nil, fmt.Errorf("unsupported wire type %d for field %d", wireType, fieldNum)
+		}
+	}
+
+	return fields, nil
+}
+
+func readVarint(b []byte) (uint64, int) {
+	var v uint64
+	var shift uint
+	for i, byt := range b {
+		v |= uint64(byt&0x7f) << shift
+		if byt&0x80 == 0 {
+			return v, i + 1
+		}
+		shift += 7
+	}
+	return 0, 0
+}
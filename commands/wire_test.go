@@ -0,0 +1,117 @@
+package commands
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestAppendVarintFieldRoundTrip(t *testing.T) {
+	tests := []uint64{0, 1, 127, 128, 300, 1 << 20, 1 << 40}
+
+	for _, v := range tests {
+		b := appendVarintField(nil, 7, v)
+
+		fields, err := parseProtoFields(b)
+		if err != nil {
+			t.Fatalf("parseProtoFields(%d) returned error: %v", v, err)
+		}
+		if len(fields) != 1 {
+			t.Fatalf("parseProtoFields(%d) = %d fields, want 1", v, len(fields))
+		}
+		if fields[0].num != 7 {
+			t.Fatalf("field num = %d, want 7", fields[0].num)
+		}
+		if fields[0].varint != v {
+			t.Fatalf("field varint = %d, want %d", fields[0].varint, v)
+		}
+	}
+}
+
+func TestAppendBytesFieldRoundTrip(t *testing.T) {
+	data := []byte("hello, vehicle")
+
+	b := appendBytesField(nil, 3, data)
+
+	fields, err := parseProtoFields(b)
+	if err != nil {
+		t.Fatalf("parseProtoFields returned error: %v", err)
+	}
+	if len(fields) != 1 {
+		t.Fatalf("parseProtoFields = %d fields, want 1", len(fields))
+	}
+	if fields[0].num != 3 {
+		t.Fatalf("field num = %d, want 3", fields[0].num)
+	}
+	if !bytes.Equal(fields[0].bytesData, data) {
+		t.Fatalf("field bytesData = %q, want %q", fields[0].bytesData, data)
+	}
+}
+
+func TestAppendMessageFieldRoundTrip(t *testing.T) {
+	inner := appendVarintField(nil, 1, 42)
+	outer := appendMessageField(nil, 2, inner)
+
+	fields, err := parseProtoFields(outer)
+	if err != nil {
+		t.Fatalf("parseProtoFields returned error: %v", err)
+	}
+	if len(fields) != 1 || fields[0].num != 2 {
+		t.Fatalf("outer fields = %+v, want one field numbered 2", fields)
+	}
+
+	innerFields, err := parseProtoFields(fields[0].bytesData)
+	if err != nil {
+		t.Fatalf("parseProtoFields(inner) returned error: %v", err)
+	}
+	if len(innerFields) != 1 || innerFields[0].num != 1 || innerFields[0].varint != 42 {
+		t.Fatalf("inner fields = %+v, want one field numbered 1 with varint 42", innerFields)
+	}
+}
+
+func TestParseProtoFieldsMultipleFields(t *testing.T) {
+	var b []byte
+	b = appendVarintField(b, 1, 10)
+	b = appendBytesField(b, 2, []byte("abc"))
+	b = appendVarintField(b, 3, 20)
+
+	fields, err := parseProtoFields(b)
+	if err != nil {
+		t.Fatalf("parseProtoFields returned error: %v", err)
+	}
+	if len(fields) != 3 {
+		t.Fatalf("parseProtoFields = %d fields, want 3", len(fields))
+	}
+	if fields[0].num != 1 || fields[0].varint != 10 {
+		t.Fatalf("fields[0] = %+v, want num 1 varint 10", fields[0])
+	}
+	if fields[1].num != 2 || !bytes.Equal(fields[1].bytesData, []byte("abc")) {
+		t.Fatalf("fields[1] = %+v, want num 2 bytesData \"abc\"", fields[1])
+	}
+	if fields[2].num != 3 || fields[2].varint != 20 {
+		t.Fatalf("fields[2] = %+v, want num 3 varint 20", fields[2])
+	}
+}
+
+func TestParseProtoFieldsTruncatedData(t *testing.T) {
+	b := appendTag(nil, 1, wireBytes)
+	b = appendVarint(b, 5) // claims 5 bytes of payload, but none follow
+
+	if _, err := parseProtoFields(b); err == nil {
+		t.Fatal("parseProtoFields() = nil error, want an error for truncated data")
+	}
+}
+
+func TestParseProtoFieldsUnsupportedWireType(t *testing.T) {
+	b := appendTag(nil, 1, 5) // wire type 5 (fixed32) isn't one parseProtoFields supports
+
+	if _, err := parseProtoFields(b); err == nil {
+		t.Fatal("parseProtoFields() = nil error, want an error for an unsupported wire type")
+	}
+}
+
+func TestReadVarintEmpty(t *testing.T) {
+	v, n := readVarint(nil)
+	if v != 0 || n != 0 {
+		t.Fatalf("readVarint(nil) = (%d, %d), want (0, 0)", v, n)
+	}
+}
@@ -0,0 +1,93 @@
+package tesla
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+type signedCommandRequest struct {
+	RoutableMessage []byte `json:"routable_message"`
+}
+
+type signedCommandResponse struct {
+	RoutableMessage []byte `json:"routable_message"`
+}
+
+// PostSignedCommand delivers an already-encoded, already-encrypted
+// vehicle-command protocol message to the car with the given VIN via
+// the [signed_command] endpoint, and returns the car's response message
+// bytes unchanged. It is the transport used by the commands subpackage
+// for cars that report VehicleCommandProtocolRequired; most callers
+// should use that package instead of calling this directly.
+//
+// [signed_command]: https://developer.tesla.com/docs/fleet-api/endpoints/vehicle-commands#signed-command
+func (c *Client) PostSignedCommand(ctx context.Context, vin string, routableMessage []byte) ([]byte, error) {
+	path := fmt.Sprintf("api/1/vehicles/%s/signed_command", vin)
+
+	respBytes, err := c.do(ctx, http.MethodPost, path, vin, nil, signedCommandRequest{RoutableMessage: routableMessage})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := decodeResponse[signedCommandResponse](respBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.RoutableMessage, nil
+}
+
+type vehiclePublicKeyResponse struct {
+	PublicKey []byte `json:"public_key"`
+}
+
+// GetVehiclePublicKey retrieves the car's current NIST P-256 public key
+// for establishing a signed-command session, via the [signed_command]
+// endpoint. Cars that have never paired a key, or that can only be
+// reached over BLE, return an error. Neither this package nor the
+// commands subpackage discovers keys over BLE.
+//
+// [signed_command]: https://developer.tesla.com/docs/fleet-api/endpoints/vehicle-commands#signed-command
+func (c *Client) GetVehiclePublicKey(ctx context.Context, vin string) ([]byte, error) {
+	path := fmt.Sprintf("api/1/vehicles/%s/signed_command", vin)
+
+	respBytes, err := c.do(ctx, http.MethodGet, path, vin, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := decodeResponse[vehiclePublicKeyResponse](respBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resp.PublicKey) == 0 {
+		return nil, fmt.Errorf("vehicle has no public key on file")
+	}
+
+	return resp.PublicKey, nil
+}
+
+// PostVehicleCommand sends a legacy REST vehicle command (one that
+// doesn't require the signed-command protocol) to the car with the
+// given VIN, using the [vehicle commands] endpoints. body is marshaled
+// as the JSON request body; pass nil for commands that take no
+// arguments.
+//
+// [vehicle commands]: https://developer.tesla.com/docs/fleet-api/endpoints/vehicle-commands
+func (c *Client) PostVehicleCommand(ctx context.Context, vin, name string, body any) (json.RawMessage, error) {
+	if err := validateCommandName(name); err != nil {
+		return nil, err
+	}
+
+	path := fmt.Sprintf("api/1/vehicles/%s/command/%s", vin, name)
+
+	respBytes, err := c.do(ctx, http.MethodPost, path, vin, nil, body)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeResponse[json.RawMessage](respBytes)
+}
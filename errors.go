@@ -0,0 +1,104 @@
+package tesla
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Sentinel errors that [APIError.Unwrap] may return, so callers can
+// branch on the failure mode with errors.Is without inspecting status
+// codes or error codes themselves.
+var (
+	// ErrInvalidToken means the access token was missing, malformed, or
+	// expired. Callers should refresh it and retry.
+	ErrInvalidToken = errors.New("tesla: invalid or expired token")
+	// ErrVehicleUnavailable means the car didn't respond in time,
+	// typically because it's asleep. Callers should call [Client.WakeUp]
+	// and retry.
+	ErrVehicleUnavailable = errors.New("tesla: vehicle unavailable")
+	// ErrVehicleOffline means the car has no connectivity at all.
+	// Retrying immediately is unlikely to help.
+	ErrVehicleOffline = errors.New("tesla: vehicle offline")
+	// ErrRateLimited means the client is being throttled. See
+	// [APIError.RetryAfter] for how long to back off.
+	ErrRateLimited = errors.New("tesla: rate limited")
+)
+
+// APIError is returned by endpoint methods when the Tesla fleet API
+// responds with a non-2xx status. It preserves the JSON error body so
+// callers can make their own retry/refresh decisions, and unwraps to one
+// of the sentinel errors in this package for the common cases.
+type APIError struct {
+	StatusCode  int
+	Code        string
+	Message     string
+	Description string
+	Raw         []byte
+
+	// RetryAfter is how long to wait before retrying, parsed from the
+	// Retry-After header. It is only meaningful when Unwrap returns
+	// [ErrRateLimited].
+	RetryAfter time.Duration
+
+	sentinel error
+}
+
+func (e *APIError) Error() string {
+	msg := e.Message
+	if msg == "" {
+		msg = http.StatusText(e.StatusCode)
+	}
+
+	if e.Description != "" {
+		return "tesla: " + msg + ": " + e.Description
+	}
+
+	return "tesla: " + msg
+}
+
+func (e *APIError) Unwrap() error {
+	return e.sentinel
+}
+
+type apiErrorBody struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+	Code             string `json:"code"`
+}
+
+// newAPIError builds an [APIError] from a non-2xx response, classifying
+// it against the package's sentinel errors where the status code or
+// response header makes that possible.
+func newAPIError(statusCode int, respBytes []byte, header http.Header) *APIError {
+	var body apiErrorBody
+	_ = json.Unmarshal(respBytes, &body) // Best effort; body may not be JSON.
+
+	apiErr := &APIError{
+		StatusCode:  statusCode,
+		Code:        body.Code,
+		Message:     body.Error,
+		Description: body.ErrorDescription,
+		Raw:         respBytes,
+	}
+
+	switch statusCode {
+	case http.StatusUnauthorized:
+		apiErr.sentinel = ErrInvalidToken
+	case http.StatusRequestTimeout:
+		apiErr.sentinel = ErrVehicleUnavailable
+	case http.StatusTooManyRequests:
+		apiErr.sentinel = ErrRateLimited
+		if ra := header.Get("Retry-After"); ra != "" {
+			if secs, err := strconv.Atoi(ra); err == nil {
+				apiErr.RetryAfter = time.Duration(secs) * time.Second
+			}
+		}
+	case http.StatusServiceUnavailable:
+		apiErr.sentinel = ErrVehicleOffline
+	}
+
+	return apiErr
+}
@@ -0,0 +1,91 @@
+package tesla
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestNewAPIErrorClassification(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		sentinel error
+	}{
+		{"unauthorized", http.StatusUnauthorized, ErrInvalidToken},
+		{"timeout", http.StatusRequestTimeout, ErrVehicleUnavailable},
+		{"rate limited", http.StatusTooManyRequests, ErrRateLimited},
+		{"service unavailable", http.StatusServiceUnavailable, ErrVehicleOffline},
+		{"unrecognized status", http.StatusBadRequest, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apiErr := newAPIError(tt.status, nil, http.Header{})
+
+			if tt.sentinel == nil {
+				if apiErr.Unwrap() != nil {
+					t.Fatalf("Unwrap() = %v, want nil", apiErr.Unwrap())
+				}
+				return
+			}
+
+			if !errors.Is(apiErr, tt.sentinel) {
+				t.Fatalf("errors.Is(apiErr, %v) = false, want true", tt.sentinel)
+			}
+		})
+	}
+}
+
+func TestNewAPIErrorRetryAfter(t *testing.T) {
+	header := http.Header{}
+	header.Set("Retry-After", "30")
+
+	apiErr := newAPIError(http.StatusTooManyRequests, nil, header)
+
+	if !errors.Is(apiErr, ErrRateLimited) {
+		t.Fatalf("errors.Is(apiErr, ErrRateLimited) = false, want true")
+	}
+
+	if apiErr.RetryAfter != 30*time.Second {
+		t.Fatalf("RetryAfter = %v, want 30s", apiErr.RetryAfter)
+	}
+}
+
+func TestNewAPIErrorRetryAfterMissingOrInvalid(t *testing.T) {
+	apiErr := newAPIError(http.StatusTooManyRequests, nil, http.Header{})
+	if apiErr.RetryAfter != 0 {
+		t.Fatalf("RetryAfter = %v, want 0 when header is absent", apiErr.RetryAfter)
+	}
+
+	header := http.Header{}
+	header.Set("Retry-After", "not-a-number")
+	apiErr = newAPIError(http.StatusTooManyRequests, nil, header)
+	if apiErr.RetryAfter != 0 {
+		t.Fatalf("RetryAfter = %v, want 0 when header is unparseable", apiErr.RetryAfter)
+	}
+}
+
+func TestAPIErrorMessage(t *testing.T) {
+	body := []byte(`{"error":"invalid_request","error_description":"vin not found","code":"E_VIN"}`)
+	apiErr := newAPIError(http.StatusBadRequest, body, http.Header{})
+
+	want := "tesla: invalid_request: vin not found"
+	if got := apiErr.Error(); got != want {
+		t.Fatalf("Error() = %q, want %q", got, want)
+	}
+
+	if apiErr.Code != "E_VIN" {
+		t.Fatalf("Code = %q, want %q", apiErr.Code, "E_VIN")
+	}
+}
+
+func TestAPIErrorMessageFallsBackToStatusText(t *testing.T) {
+	apiErr := newAPIError(http.StatusBadRequest, []byte("not json"), http.Header{})
+
+	want := "tesla: " + http.StatusText(http.StatusBadRequest)
+	if got := apiErr.Error(); got != want {
+		t.Fatalf("Error() = %q, want %q", got, want)
+	}
+}
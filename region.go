@@ -0,0 +1,82 @@
+package tesla
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sync"
+)
+
+// RegionCache remembers which fleet API base URL a VIN has been
+// resolved to, so that a client which has already hit a 421 region
+// redirect for that VIN doesn't have to pay the wrong-region round trip
+// again. Implementations must be safe for concurrent use.
+type RegionCache interface {
+	// Get returns the cached base URL for key, and whether an entry was
+	// found.
+	Get(key string) (*url.URL, bool)
+	// Set records base as the resolved base URL for key.
+	Set(key string, base *url.URL)
+}
+
+// NewInMemoryRegionCache returns a [RegionCache] backed by a map held in
+// process memory. This is the default cache used by [New]; pass
+// [WithRegionCache] with a different implementation to persist the
+// mapping across process restarts.
+func NewInMemoryRegionCache() RegionCache {
+	return &inMemoryRegionCache{
+		entries: make(map[string]*url.URL),
+	}
+}
+
+type inMemoryRegionCache struct {
+	mu      sync.RWMutex
+	entries map[string]*url.URL
+}
+
+func (c *inMemoryRegionCache) Get(key string) (*url.URL, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	u, ok := c.entries[key]
+	return u, ok
+}
+
+func (c *inMemoryRegionCache) Set(key string, base *url.URL) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[key] = base
+}
+
+// regionBaseURLs maps the region names Tesla's fleet API returns in a
+// 421 response to the base URL serving that region. See the
+// [fleet-api regions] documentation.
+//
+// [fleet-api regions]: https://developer.tesla.com/docs/fleet-api/getting-started/base-urls
+var regionBaseURLs = map[string]string{
+	"na": "https://fleet-api.prd.na.vn.cloud.tesla.com",
+	"eu": "https://fleet-api.prd.eu.vn.cloud.tesla.com",
+	"cn": "https://fleet-api.prd.cn.vn.cloud.tesla.cn",
+}
+
+type regionRedirectResponse struct {
+	Error  string `json:"error"`
+	Region string `json:"region"`
+}
+
+// parseRegionRedirect extracts the base URL to retry against from the
+// body of a 421 response.
+func parseRegionRedirect(respBytes []byte) (*url.URL, error) {
+	var body regionRedirectResponse
+	if err := json.Unmarshal(respBytes, &body); err != nil {
+		return nil, fmt.Errorf("failed to parse region redirect body: %w", err)
+	}
+
+	raw, ok := regionBaseURLs[body.Region]
+	if !ok {
+		return nil, fmt.Errorf("unrecognized region %q", body.Region)
+	}
+
+	return url.Parse(raw)
+}
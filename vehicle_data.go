@@ -0,0 +1,368 @@
+package tesla
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+type chargeState struct {
+	BatteryLevel     int     `json:"battery_level"`
+	BatteryRange     float64 `json:"battery_range"`
+	ChargingState    string  `json:"charging_state"`
+	ChargeLimitSOC   int     `json:"charge_limit_soc"`
+	ChargerVoltage   *int    `json:"charger_voltage"`
+	ChargerPower     *int    `json:"charger_power"`
+	TimeToFullCharge float64 `json:"time_to_full_charge"`
+}
+
+// ChargeState holds the subset of the [charge_state] vehicle data
+// endpoint that most integrations care about.
+//
+// [charge_state]: https://developer.tesla.com/docs/fleet-api/endpoints/vehicle-endpoints#charge-state
+type ChargeState struct {
+	BatteryLevel     int
+	BatteryRange     float64
+	ChargingState    string
+	ChargeLimitSOC   int
+	ChargerVoltage   *int
+	ChargerPower     *int
+	TimeToFullCharge float64
+}
+
+type climateState struct {
+	InsideTemp        *float64 `json:"inside_temp"`
+	OutsideTemp       *float64 `json:"outside_temp"`
+	IsClimateOn       bool     `json:"is_climate_on"`
+	DriverTempSetting float64  `json:"driver_temp_setting"`
+}
+
+// ClimateState holds the subset of the [climate_state] vehicle data
+// endpoint that most integrations care about.
+//
+// [climate_state]: https://developer.tesla.com/docs/fleet-api/endpoints/vehicle-endpoints#climate-state
+type ClimateState struct {
+	InsideTemp        *float64
+	OutsideTemp       *float64
+	IsClimateOn       bool
+	DriverTempSetting float64
+}
+
+type driveState struct {
+	Latitude   float64  `json:"latitude"`
+	Longitude  float64  `json:"longitude"`
+	Heading    int      `json:"heading"`
+	Speed      *float64 `json:"speed"`
+	ShiftState *string  `json:"shift_state"`
+}
+
+// DriveState holds the subset of the [drive_state] vehicle data endpoint
+// that most integrations care about.
+//
+// [drive_state]: https://developer.tesla.com/docs/fleet-api/endpoints/vehicle-endpoints#drive-state
+type DriveState struct {
+	Latitude   float64
+	Longitude  float64
+	Heading    int
+	Speed      *float64
+	ShiftState *string
+}
+
+type guiSettings struct {
+	GuiDistanceUnits    string `json:"gui_distance_units"`
+	GuiTemperatureUnits string `json:"gui_temperature_units"`
+	Gui24HourTime       bool   `json:"gui_24_hour_time"`
+}
+
+// GuiSettings holds the [gui_settings] vehicle data endpoint.
+//
+// [gui_settings]: https://developer.tesla.com/docs/fleet-api/endpoints/vehicle-endpoints#gui-settings
+type GuiSettings struct {
+	GuiDistanceUnits    string
+	GuiTemperatureUnits string
+	Gui24HourTime       bool
+}
+
+type vehicleState struct {
+	Locked     bool    `json:"locked"`
+	Odometer   float64 `json:"odometer"`
+	SentryMode *bool   `json:"sentry_mode"`
+}
+
+// VehicleState holds the subset of the [vehicle_state] vehicle data
+// endpoint that most integrations care about.
+//
+// [vehicle_state]: https://developer.tesla.com/docs/fleet-api/endpoints/vehicle-endpoints#vehicle-state
+type VehicleState struct {
+	Locked     bool
+	Odometer   float64
+	SentryMode *bool
+}
+
+type vehicleConfig struct {
+	CarType       string `json:"car_type"`
+	ExteriorColor string `json:"exterior_color"`
+	WheelType     string `json:"wheel_type"`
+}
+
+// VehicleConfig holds the subset of the [vehicle_config] vehicle data
+// endpoint that most integrations care about.
+//
+// [vehicle_config]: https://developer.tesla.com/docs/fleet-api/endpoints/vehicle-endpoints#vehicle-config
+type VehicleConfig struct {
+	CarType       string
+	ExteriorColor string
+	WheelType     string
+}
+
+type locationData struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// LocationData holds the [location_data] vehicle data endpoint.
+//
+// [location_data]: https://developer.tesla.com/docs/fleet-api/endpoints/vehicle-endpoints#location-data
+type LocationData struct {
+	Latitude  float64
+	Longitude float64
+}
+
+type vehicleDataResponse struct {
+	ChargeState   *chargeState   `json:"charge_state"`
+	ClimateState  *climateState  `json:"climate_state"`
+	DriveState    *driveState    `json:"drive_state"`
+	GuiSettings   *guiSettings   `json:"gui_settings"`
+	VehicleState  *vehicleState  `json:"vehicle_state"`
+	VehicleConfig *vehicleConfig `json:"vehicle_config"`
+	LocationData  *locationData  `json:"location_data"`
+}
+
+// VehicleData is the combined response of the [vehicle_data] endpoint.
+// Only the sub-objects passed to [Client.GetVehicleData] as endpoints
+// are populated; the rest are nil.
+//
+// [vehicle_data]: https://developer.tesla.com/docs/fleet-api/endpoints/vehicle-endpoints#vehicle-data
+type VehicleData struct {
+	ChargeState   *ChargeState
+	ClimateState  *ClimateState
+	DriveState    *DriveState
+	GuiSettings   *GuiSettings
+	VehicleState  *VehicleState
+	VehicleConfig *VehicleConfig
+	LocationData  *LocationData
+}
+
+// GetVehicleData retrieves a combination of vehicle state sub-objects
+// from the [vehicle_data] endpoint. Valid values for endpoints are
+// "charge_state", "climate_state", "drive_state", "gui_settings",
+// "vehicle_state", "vehicle_config", and "location_data"; only the
+// requested sub-objects are populated on the returned [VehicleData].
+//
+// [vehicle_data]: https://developer.tesla.com/docs/fleet-api/endpoints/vehicle-endpoints#vehicle-data
+func (c *Client) GetVehicleData(ctx context.Context, vin string, endpoints ...string) (*VehicleData, error) {
+	var query url.Values
+	if len(endpoints) != 0 {
+		query = url.Values{"endpoints": {strings.Join(endpoints, ";")}}
+	}
+
+	path := fmt.Sprintf("api/1/vehicles/%s/vehicle_data", vin)
+
+	respBytes, err := c.do(ctx, http.MethodGet, path, vin, query, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	vd, err := decodeResponse[vehicleDataResponse](respBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &VehicleData{}
+
+	if vd.ChargeState != nil {
+		out.ChargeState = &ChargeState{
+			BatteryLevel:     vd.ChargeState.BatteryLevel,
+			BatteryRange:     vd.ChargeState.BatteryRange,
+			ChargingState:    vd.ChargeState.ChargingState,
+			ChargeLimitSOC:   vd.ChargeState.ChargeLimitSOC,
+			ChargerVoltage:   vd.ChargeState.ChargerVoltage,
+			ChargerPower:     vd.ChargeState.ChargerPower,
+			TimeToFullCharge: vd.ChargeState.TimeToFullCharge,
+		}
+	}
+	if vd.ClimateState != nil {
+		out.ClimateState = &ClimateState{
+			InsideTemp:        vd.ClimateState.InsideTemp,
+			OutsideTemp:       vd.ClimateState.OutsideTemp,
+			IsClimateOn:       vd.ClimateState.IsClimateOn,
+			DriverTempSetting: vd.ClimateState.DriverTempSetting,
+		}
+	}
+	if vd.DriveState != nil {
+		out.DriveState = &DriveState{
+			Latitude:   vd.DriveState.Latitude,
+			Longitude:  vd.DriveState.Longitude,
+			Heading:    vd.DriveState.Heading,
+			Speed:      vd.DriveState.Speed,
+			ShiftState: vd.DriveState.ShiftState,
+		}
+	}
+	if vd.GuiSettings != nil {
+		out.GuiSettings = &GuiSettings{
+			GuiDistanceUnits:    vd.GuiSettings.GuiDistanceUnits,
+			GuiTemperatureUnits: vd.GuiSettings.GuiTemperatureUnits,
+			Gui24HourTime:       vd.GuiSettings.Gui24HourTime,
+		}
+	}
+	if vd.VehicleState != nil {
+		out.VehicleState = &VehicleState{
+			Locked:     vd.VehicleState.Locked,
+			Odometer:   vd.VehicleState.Odometer,
+			SentryMode: vd.VehicleState.SentryMode,
+		}
+	}
+	if vd.VehicleConfig != nil {
+		out.VehicleConfig = &VehicleConfig{
+			CarType:       vd.VehicleConfig.CarType,
+			ExteriorColor: vd.VehicleConfig.ExteriorColor,
+			WheelType:     vd.VehicleConfig.WheelType,
+		}
+	}
+	if vd.LocationData != nil {
+		out.LocationData = &LocationData{
+			Latitude:  vd.LocationData.Latitude,
+			Longitude: vd.LocationData.Longitude,
+		}
+	}
+
+	return out, nil
+}
+
+// GetMobileEnabled reports whether the car with the given VIN has the
+// mobile access setting enabled, using the [mobile_enabled] endpoint.
+//
+// [mobile_enabled]: https://developer.tesla.com/docs/fleet-api/endpoints/vehicle-endpoints#mobile-enabled
+func (c *Client) GetMobileEnabled(ctx context.Context, vin string) (bool, error) {
+	path := fmt.Sprintf("api/1/vehicles/%s/mobile_enabled", vin)
+
+	respBytes, err := c.do(ctx, http.MethodGet, path, vin, nil, nil)
+	if err != nil {
+		return false, err
+	}
+
+	return decodeResponse[bool](respBytes)
+}
+
+// Supercharger describes a single charging site returned by
+// [Client.GetNearbyChargingSites].
+type Supercharger struct {
+	Name            string  `json:"name"`
+	Type            string  `json:"type"`
+	DistanceMiles   float64 `json:"distance_miles"`
+	AvailableStalls int     `json:"available_stalls"`
+	TotalStalls     int     `json:"total_stalls"`
+	SiteClosed      bool    `json:"site_closed"`
+}
+
+type nearbyChargingSites struct {
+	Superchargers             []Supercharger `json:"superchargers"`
+	DestinationCharging       []Supercharger `json:"destination_charging"`
+	CongestionSyncTimeUtcSecs int            `json:"congestion_sync_time_utc_secs"`
+}
+
+// NearbyChargingSites is the response of the [nearby_charging_sites]
+// endpoint.
+//
+// [nearby_charging_sites]: https://developer.tesla.com/docs/fleet-api/endpoints/vehicle-endpoints#nearby-charging-sites
+type NearbyChargingSites struct {
+	Superchargers             []Supercharger
+	DestinationCharging       []Supercharger
+	CongestionSyncTimeUtcSecs int
+}
+
+// GetNearbyChargingSites lists the supercharger and destination charging
+// sites near the car with the given VIN, using the
+// [nearby_charging_sites] endpoint.
+//
+// [nearby_charging_sites]: https://developer.tesla.com/docs/fleet-api/endpoints/vehicle-endpoints#nearby-charging-sites
+func (c *Client) GetNearbyChargingSites(ctx context.Context, vin string) (*NearbyChargingSites, error) {
+	path := fmt.Sprintf("api/1/vehicles/%s/nearby_charging_sites", vin)
+
+	respBytes, err := c.do(ctx, http.MethodGet, path, vin, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	sites, err := decodeResponse[nearbyChargingSites](respBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return &NearbyChargingSites{
+		Superchargers:             sites.Superchargers,
+		DestinationCharging:       sites.DestinationCharging,
+		CongestionSyncTimeUtcSecs: sites.CongestionSyncTimeUtcSecs,
+	}, nil
+}
+
+// ReleaseNote is a single firmware release note entry, as returned by
+// [Client.GetReleaseNotes].
+type ReleaseNote struct {
+	Version     string `json:"version"`
+	Title       string `json:"title"`
+	Subtitle    string `json:"subtitle"`
+	Description string `json:"description"`
+}
+
+// GetReleaseNotes retrieves the firmware release notes for the car with
+// the given VIN, using the [release_notes] endpoint. Pass staged to
+// retrieve notes for an update that has been downloaded but not yet
+// installed.
+//
+// [release_notes]: https://developer.tesla.com/docs/fleet-api/endpoints/vehicle-endpoints#release-notes
+func (c *Client) GetReleaseNotes(ctx context.Context, vin string, staged bool) ([]ReleaseNote, error) {
+	path := fmt.Sprintf("api/1/vehicles/%s/release_notes", vin)
+	query := url.Values{"staged": {strconv.FormatBool(staged)}}
+
+	respBytes, err := c.do(ctx, http.MethodGet, path, vin, query, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeResponse[[]ReleaseNote](respBytes)
+}
+
+// WakeUpResult reports the state of a car as returned by
+// [Client.WakeUp].
+type WakeUpResult struct {
+	ID        int64  `json:"id"`
+	VehicleID int64  `json:"vehicle_id"`
+	VIN       string `json:"vin"`
+	State     string `json:"state"`
+}
+
+// WakeUp asks a sleeping car to wake up, using the [wake_up] endpoint.
+// The returned state is typically "waking" immediately after the call;
+// callers should call WakeUp again until the returned state is
+// "online".
+//
+// [wake_up]: https://developer.tesla.com/docs/fleet-api/endpoints/vehicle-endpoints#wake-up
+func (c *Client) WakeUp(ctx context.Context, vin string) (*WakeUpResult, error) {
+	path := fmt.Sprintf("api/1/vehicles/%s/wake_up", vin)
+
+	respBytes, err := c.do(ctx, http.MethodPost, path, vin, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := decodeResponse[WakeUpResult](respBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
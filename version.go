@@ -0,0 +1,13 @@
+package tesla
+
+import (
+	_ "embed"
+	"strings"
+)
+
+//go:embed version.txt
+var version string
+
+// defaultUserAgent is sent on every request unless overridden with
+// [WithUserAgent].
+var defaultUserAgent = "tesla-go/" + strings.TrimSpace(version)